@@ -0,0 +1,31 @@
+package checker
+
+import "context"
+
+// APIVerifier checks mailbox existence for mail providers whose SMTP servers return a
+// uniform 250 OK for RCPT TO regardless of whether the mailbox exists, making the SMTP
+// probe in smtpVerifyMailbox useless against them.
+type APIVerifier interface {
+	// IsSupported reports whether this verifier knows how to check mailboxes hosted on
+	// mxHost (e.g. a suffix match like "*.google.com").
+	IsSupported(mxHost string) bool
+	// Check probes whether local@domain exists using a provider-specific API instead of SMTP.
+	Check(ctx context.Context, domain, local string) (*ValidationResult, error)
+}
+
+// RegisterAPIVerifier adds v to the set of provider-specific verifiers consulted before
+// falling back to SMTP RCPT TO probing.
+func (ec *EmailChecker) RegisterAPIVerifier(v APIVerifier) {
+	ec.apiVerifiers = append(ec.apiVerifiers, v)
+}
+
+// matchAPIVerifier returns the first registered verifier that supports mxHost, or nil if
+// none of them do.
+func (ec *EmailChecker) matchAPIVerifier(mxHost string) APIVerifier {
+	for _, v := range ec.apiVerifiers {
+		if v.IsSupported(mxHost) {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kelvinzer0/secure-email-validator/internal/config"
+)
+
+type fakeAPIVerifier struct {
+	suffix string
+	result *ValidationResult
+}
+
+func (f *fakeAPIVerifier) IsSupported(mxHost string) bool {
+	return len(mxHost) >= len(f.suffix) && mxHost[len(mxHost)-len(f.suffix):] == f.suffix
+}
+
+func (f *fakeAPIVerifier) Check(ctx context.Context, domain, local string) (*ValidationResult, error) {
+	return f.result, nil
+}
+
+func TestEmailChecker_matchAPIVerifier(t *testing.T) {
+	ec := NewEmailChecker(config.DefaultConfig())
+	gmail := &fakeAPIVerifier{suffix: ".google.com", result: &ValidationResult{Deliverability: Deliverable}}
+	yahoo := &fakeAPIVerifier{suffix: ".yahoodns.net", result: &ValidationResult{Deliverability: Deliverable}}
+	ec.RegisterAPIVerifier(gmail)
+	ec.RegisterAPIVerifier(yahoo)
+
+	tests := []struct {
+		mxHost string
+		want   APIVerifier
+	}{
+		{"aspmx.l.google.com", gmail},
+		{"mta5.am0.yahoodns.net", yahoo},
+		{"mail.example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mxHost, func(t *testing.T) {
+			if got := ec.matchAPIVerifier(tt.mxHost); got != tt.want {
+				t.Errorf("matchAPIVerifier(%q) = %v, want %v", tt.mxHost, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestTLSAMatchesCert(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("fake certificate bytes")}
+	digest := sha256.Sum256(cert.Raw)
+
+	tests := []struct {
+		name string
+		rr   *dns.TLSA
+		want bool
+	}{
+		{
+			name: "matching type 1 full cert sha256 matches",
+			rr:   &dns.TLSA{Selector: 0, MatchingType: 1, Certificate: fmt.Sprintf("%x", digest)},
+			want: true,
+		},
+		{
+			name: "matching type 1 wrong digest does not match",
+			rr:   &dns.TLSA{Selector: 0, MatchingType: 1, Certificate: "0000"},
+			want: false,
+		},
+		{
+			name: "matching type 0 raw cert matches",
+			rr:   &dns.TLSA{Selector: 0, MatchingType: 0, Certificate: fmt.Sprintf("%x", cert.Raw)},
+			want: true,
+		},
+		{
+			name: "unsupported selector does not match",
+			rr:   &dns.TLSA{Selector: 2, MatchingType: 1, Certificate: fmt.Sprintf("%x", digest)},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tlsaMatchesCert(tt.rr, cert); got != tt.want {
+				t.Errorf("tlsaMatchesCert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTLSAMatchesChain(t *testing.T) {
+	leaf := &x509.Certificate{Raw: []byte("leaf certificate bytes")}
+	intermediate := &x509.Certificate{Raw: []byte("intermediate CA bytes")}
+	chain := []*x509.Certificate{leaf, intermediate}
+
+	leafDigest := fmt.Sprintf("%x", sha256.Sum256(leaf.Raw))
+	intermediateDigest := fmt.Sprintf("%x", sha256.Sum256(intermediate.Raw))
+
+	tests := []struct {
+		name  string
+		rr    *dns.TLSA
+		chain []*x509.Certificate
+		want  bool
+	}{
+		{
+			name:  "DANE-EE matches the leaf",
+			rr:    &dns.TLSA{Usage: 3, Selector: 0, MatchingType: 1, Certificate: leafDigest},
+			chain: chain,
+			want:  true,
+		},
+		{
+			name:  "DANE-EE does not match an intermediate",
+			rr:    &dns.TLSA{Usage: 3, Selector: 0, MatchingType: 1, Certificate: intermediateDigest},
+			chain: chain,
+			want:  false,
+		},
+		{
+			name:  "DANE-TA matches a non-leaf certificate in the chain",
+			rr:    &dns.TLSA{Usage: 2, Selector: 0, MatchingType: 1, Certificate: intermediateDigest},
+			chain: chain,
+			want:  true,
+		},
+		{
+			name:  "DANE-TA also matches the leaf",
+			rr:    &dns.TLSA{Usage: 2, Selector: 0, MatchingType: 1, Certificate: leafDigest},
+			chain: chain,
+			want:  true,
+		},
+		{
+			name:  "PKIX-EE fails closed when the chain is not WebPKI-trusted",
+			rr:    &dns.TLSA{Usage: 1, Selector: 0, MatchingType: 1, Certificate: leafDigest},
+			chain: chain,
+			want:  false,
+		},
+		{
+			name:  "PKIX-TA fails closed when the chain is not WebPKI-trusted",
+			rr:    &dns.TLSA{Usage: 0, Selector: 0, MatchingType: 1, Certificate: intermediateDigest},
+			chain: chain,
+			want:  false,
+		},
+		{
+			name:  "unsupported usage does not match",
+			rr:    &dns.TLSA{Usage: 4, Selector: 0, MatchingType: 1, Certificate: leafDigest},
+			chain: chain,
+			want:  false,
+		},
+		{
+			name:  "empty chain never matches",
+			rr:    &dns.TLSA{Usage: 3, Selector: 0, MatchingType: 1, Certificate: leafDigest},
+			chain: nil,
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tlsaMatchesChain(tt.rr, "mail.example.com", tt.chain); got != tt.want {
+				t.Errorf("tlsaMatchesChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/kelvinzer0/secure-email-validator/internal/config"
+	"github.com/miekg/dns"
+)
+
+func TestDsMatchesAnyKey(t *testing.T) {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.RSASHA256,
+		PublicKey: "AwEAAagAIjxijrf9nY9Pl9k0RkHp5Fc0QsDx0VEWwBdE0Rsf5r+e",
+	}
+	ds := key.ToDS(dns.SHA256)
+
+	otherKey := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.RSASHA256,
+		PublicKey: "AwEAAcW1ybxrAKkr6SKfJM+EBVaYpxikUL4cpI1BoOTK/35Mx9E=",
+	}
+
+	tests := []struct {
+		name  string
+		dsRRs []dns.RR
+		keys  []dns.RR
+		want  bool
+	}{
+		{
+			name:  "matching DS and DNSKEY forms a valid link",
+			dsRRs: []dns.RR{ds},
+			keys:  []dns.RR{key},
+			want:  true,
+		},
+		{
+			name:  "DNSKEY that doesn't correspond to the DS does not match",
+			dsRRs: []dns.RR{ds},
+			keys:  []dns.RR{otherKey},
+			want:  false,
+		},
+		{
+			name:  "no DNSKEY records at all",
+			dsRRs: []dns.RR{ds},
+			keys:  nil,
+			want:  false,
+		},
+		{
+			name:  "no DS records at all",
+			dsRRs: nil,
+			keys:  []dns.RR{key},
+			want:  false,
+		},
+		{
+			name:  "non-DS/DNSKEY RRs are ignored rather than matched",
+			dsRRs: []dns.RR{&dns.A{Hdr: dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeA}}},
+			keys:  []dns.RR{key},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dsMatchesAnyKey(tt.dsRRs, tt.keys); got != tt.want {
+				t.Errorf("dsMatchesAnyKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeDNSSECServer is a minimal UDP DNS server used to exercise queryDNSSEC's branching
+// without depending on live resolvers. handler builds the response for each question.
+func fakeDNSSECServer(t *testing.T, handler func(q dns.Question, ad bool) *dns.Msg) (addr string, shutdown func()) {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start fake DNS server: %v", err)
+	}
+
+	server := &dns.Server{PacketConn: pc}
+	server.Handler = dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		q := r.Question[0]
+		resp := handler(q, r.CheckingDisabled == false)
+		resp.SetReply(r)
+		w.WriteMsg(resp)
+	})
+
+	go server.ActivateAndServe()
+
+	return pc.LocalAddr().String(), func() { server.Shutdown() }
+}
+
+// fakeSOA builds a minimally valid SOA record for name: miekg/dns refuses to pack a SOA
+// whose Ns/Mbox are empty or whose uint32 fields are unset, so every fake server response
+// needs one of these rather than a bare &dns.SOA{Hdr: ...}.
+func fakeSOA(name string) *dns.SOA {
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: name, Rrtype: dns.TypeSOA, Class: dns.ClassINET},
+		Ns:      "ns1." + name,
+		Mbox:    "hostmaster." + name,
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  604800,
+		Minttl:  300,
+	}
+}
+
+func TestQueryDNSSEC_NoRRSIG_LaxModeTrustsADBit(t *testing.T) {
+	addr, shutdown := fakeDNSSECServer(t, func(q dns.Question, _ bool) *dns.Msg {
+		resp := new(dns.Msg)
+		resp.AuthenticatedData = true
+		if q.Qtype == dns.TypeSOA {
+			resp.Answer = []dns.RR{fakeSOA(q.Name)}
+		}
+		return resp
+	})
+	defer shutdown()
+
+	ec := NewEmailChecker(&config.Config{Timeout: 2, DNSSECStrict: false})
+	status, err := ec.queryDNSSEC(context.Background(), "example.com", addr)
+	if err != nil {
+		t.Fatalf("queryDNSSEC returned error: %v", err)
+	}
+	if status != DNSSECSecure {
+		t.Errorf("queryDNSSEC() in lax mode with AD bit set = %q, want %q", status, DNSSECSecure)
+	}
+}
+
+func TestQueryDNSSEC_NoRRSIG_StrictModeIgnoresADBit(t *testing.T) {
+	addr, shutdown := fakeDNSSECServer(t, func(q dns.Question, _ bool) *dns.Msg {
+		resp := new(dns.Msg)
+		// A forged AD bit from an on-path attacker must not be trusted in strict mode.
+		resp.AuthenticatedData = true
+		if q.Qtype == dns.TypeSOA {
+			resp.Answer = []dns.RR{fakeSOA(q.Name)}
+		}
+		// No DS records published anywhere in the chain, so the zone is genuinely insecure.
+		return resp
+	})
+	defer shutdown()
+
+	ec := NewEmailChecker(&config.Config{Timeout: 2, DNSSECStrict: true})
+	status, err := ec.queryDNSSEC(context.Background(), "example.com", addr)
+	if err != nil {
+		t.Fatalf("queryDNSSEC returned error: %v", err)
+	}
+	if status != DNSSECInsecure {
+		t.Errorf("queryDNSSEC() in strict mode with no RRSIG and no DS chain = %q, want %q", status, DNSSECInsecure)
+	}
+}
+
+func TestQueryDNSSEC_NoRRSIG_StrictModeBogusWhenDSChainPresent(t *testing.T) {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.RSASHA256,
+		PublicKey: "AwEAAagAIjxijrf9nY9Pl9k0RkHp5Fc0QsDx0VEWwBdE0Rsf5r+e",
+	}
+	ds := key.ToDS(dns.SHA256)
+
+	addr, shutdown := fakeDNSSECServer(t, func(q dns.Question, _ bool) *dns.Msg {
+		resp := new(dns.Msg)
+		switch q.Qtype {
+		case dns.TypeSOA:
+			resp.AuthenticatedData = true
+			resp.Answer = []dns.RR{fakeSOA(q.Name)}
+		case dns.TypeDS:
+			resp.Answer = []dns.RR{ds}
+		case dns.TypeDNSKEY:
+			resp.Answer = []dns.RR{key}
+		}
+		return resp
+	})
+	defer shutdown()
+
+	ec := NewEmailChecker(&config.Config{Timeout: 2, DNSSECStrict: true})
+	status, err := ec.queryDNSSEC(context.Background(), "example.com", addr)
+	if err != nil {
+		t.Fatalf("queryDNSSEC returned error: %v", err)
+	}
+	// A DS/DNSKEY chain exists (the zone is signed) but the SOA answer came back with no
+	// RRSIG covering it, so the signature itself could not be verified: bogus, not secure.
+	if status != DNSSECBogus {
+		t.Errorf("queryDNSSEC() in strict mode with a DS chain but no SOA RRSIG = %q, want %q", status, DNSSECBogus)
+	}
+}
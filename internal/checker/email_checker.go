@@ -1,11 +1,17 @@
 package checker
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"net/smtp"
-	"os/exec"
+	"net/textproto"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,35 +20,73 @@ import (
 
 // EmailChecker handles email validation
 type EmailChecker struct {
-	config *config.Config
+	config       *config.Config
+	apiVerifiers []APIVerifier
+	resolver     *Resolver
 }
 
+// Deliverability classifies the outcome of an SMTP mailbox existence probe
+type Deliverability string
+
+const (
+	Deliverable   Deliverability = "deliverable"
+	Undeliverable Deliverability = "undeliverable"
+	Unknown       Deliverability = "unknown"
+)
+
 // ValidationResult contains the result of email validation
 type ValidationResult struct {
-	Valid             bool   `json:"valid"`
-	Reason            string `json:"reason"`
-	NormalizedEmail   string `json:"normalized_email"`
-	Domain            string `json:"domain"`
-	HasMXRecord       bool   `json:"has_mx_record"`
-	HasDNSSEC         bool   `json:"has_dnssec"`
-	PrimaryMXServer   string `json:"primary_mx_server"`
-	SupportsSTARTTLS  bool   `json:"supports_starttls"`
+	Valid              bool           `json:"valid"`
+	Reason             string         `json:"reason"`
+	NormalizedEmail    string         `json:"normalized_email"`
+	Domain             string         `json:"domain"`
+	HasMXRecord        bool           `json:"has_mx_record"`
+	DNSSECStatus       DNSSECStatus   `json:"dnssec_status"`
+	PrimaryMXServer    string         `json:"primary_mx_server"`
+	AttemptedMXServers []string       `json:"attempted_mx_servers"`
+	MisconfiguredMX    bool           `json:"misconfigured_mx"`
+	MXIssues           []string       `json:"mx_issues"`
+	SupportsSTARTTLS   bool           `json:"supports_starttls"`
+	MTASTSMode         string         `json:"mta_sts_mode"`
+	MTASTSValid        bool           `json:"mta_sts_valid"`
+	DANEEnabled        bool           `json:"dane_enabled"`
+	DANEValid          bool           `json:"dane_valid"`
+	Deliverability     Deliverability `json:"deliverability"`
+	CatchAll           bool           `json:"catch_all"`
 }
 
-// NewEmailChecker creates a new EmailChecker instance
+// NewEmailChecker creates a new EmailChecker instance with its own Resolver cache
 func NewEmailChecker(cfg *config.Config) *EmailChecker {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
-	return &EmailChecker{
-		config: cfg,
+	return NewEmailCheckerWithResolver(cfg, NewResolver(cfg.CacheTTL))
+}
+
+// NewEmailCheckerWithResolver creates an EmailChecker that reuses an existing Resolver
+// instead of creating its own, letting callers such as the HTTP server share MX/TXT/
+// DNSSEC cache state across many short-lived EmailChecker instances.
+func NewEmailCheckerWithResolver(cfg *config.Config, resolver *Resolver) *EmailChecker {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	ec := &EmailChecker{
+		config:   cfg,
+		resolver: resolver,
+	}
+	if cfg.EnableAPIVerifiers {
+		ec.RegisterAPIVerifier(NewGmailVerifier())
+		ec.RegisterAPIVerifier(NewYahooVerifier())
 	}
+	return ec
 }
 
-// ValidateEmail performs comprehensive email validation
-func (ec *EmailChecker) ValidateEmail(email string) *ValidationResult {
+// ValidateEmail performs comprehensive email validation. The supplied ctx bounds every
+// network operation it performs (DNS lookups, SMTP dials, API verifier HTTP calls), so
+// a client disconnect or deadline stops outstanding work instead of leaking goroutines.
+func (ec *EmailChecker) ValidateEmail(ctx context.Context, email string) *ValidationResult {
 	result := &ValidationResult{}
-	
+
 	// Basic email format validation
 	if !ec.isValidEmailFormat(email) {
 		result.Valid = false
@@ -56,7 +100,7 @@ func (ec *EmailChecker) ValidateEmail(email string) *ValidationResult {
 	result.Domain = ec.extractDomain(result.NormalizedEmail)
 
 	// Check MX record
-	result.HasMXRecord = ec.hasMXRecord(result.Domain)
+	result.HasMXRecord = ec.hasMXRecord(ctx, result.Domain)
 	if !result.HasMXRecord {
 		result.Valid = false
 		result.Reason = "Domain doesn't have MX record"
@@ -64,29 +108,109 @@ func (ec *EmailChecker) ValidateEmail(email string) *ValidationResult {
 	}
 
 	// Check DNSSEC
-	result.HasDNSSEC = ec.hasDNSSEC(result.Domain)
-	if !result.HasDNSSEC {
+	result.DNSSECStatus = ec.checkDNSSEC(ctx, result.Domain)
+	if result.DNSSECStatus != DNSSECSecure {
 		result.Valid = false
 		result.Reason = "Domain doesn't support DNSSEC"
 		return result
 	}
 
-	// Get primary MX server
-	result.PrimaryMXServer = ec.getPrimaryMXServer(result.Domain)
-	if result.PrimaryMXServer == "" {
+	// Resolve and rank MX hosts in preference order
+	mxServers, misconfigured, mxIssues := ec.resolveMXServers(ctx, result.Domain)
+	result.MisconfiguredMX = misconfigured
+	result.MXIssues = mxIssues
+	if len(mxServers) == 0 {
 		result.Valid = false
-		result.Reason = "Failed to get MX server"
+		if misconfigured {
+			result.Reason = strings.Join(mxIssues, "; ")
+		} else {
+			result.Reason = "Failed to get MX server"
+		}
 		return result
 	}
+	result.AttemptedMXServers = mxServers
+	result.PrimaryMXServer = mxServers[0]
 
-	// Check STARTTLS support
-	result.SupportsSTARTTLS = ec.smtpSupportsSTARTTLS(result.PrimaryMXServer)
+	if misconfigured {
+		result.Valid = false
+		result.Reason = strings.Join(mxIssues, "; ")
+		return result
+	}
+
+	local := ec.extractLocalPart(result.NormalizedEmail)
+
+	// Providers like Gmail and Yahoo return a uniform 250 OK for any RCPT TO, so consult
+	// a registered API verifier before falling back to the SMTP probe below. A verifier
+	// hit only replaces the RCPT TO mailbox probe further down. An undeliverable verdict
+	// returns at once; otherwise STARTTLS and the MTA-STS/DANE policy checks still run
+	// against the live MX like any other domain.
+	apiVerified := false
+	if v := ec.matchAPIVerifier(strings.ToLower(result.PrimaryMXServer)); v != nil {
+		apiResult, err := v.Check(ctx, result.Domain, local)
+		if err != nil {
+			if ec.config.Verbose {
+				fmt.Printf("API verifier failed for %s: %v\n", result.Domain, err)
+			}
+		} else {
+			apiVerified = true
+			result.Deliverability = apiResult.Deliverability
+			result.CatchAll = apiResult.CatchAll
+			if result.Deliverability == Undeliverable {
+				result.Valid = false
+				result.Reason = "Mailbox does not exist"
+				return result
+			}
+		}
+	}
+
+	// Try each MX host in preference order until one connects and supports STARTTLS,
+	// so a single unreachable or misconfigured low-preference host doesn't fail
+	// validation outright.
+	for _, mx := range mxServers {
+		if ec.smtpSupportsSTARTTLS(ctx, mx) {
+			result.PrimaryMXServer = mx
+			result.SupportsSTARTTLS = true
+			break
+		}
+	}
 	if !result.SupportsSTARTTLS {
 		result.Valid = false
 		result.Reason = "SMTP server doesn't support STARTTLS"
 		return result
 	}
 
+	// MTA-STS (RFC 8461) and DANE (RFC 6698) are additional, independent transport
+	// security policies a domain may publish on top of STARTTLS. STARTTLS alone can be
+	// stripped by an on-path attacker, so when a domain declares one of these policies we
+	// only consider it valid if the live MX actually satisfies it.
+	result.MTASTSMode, result.MTASTSValid = ec.checkMTASTS(ctx, result.Domain, result.PrimaryMXServer)
+	result.DANEEnabled, result.DANEValid = ec.checkDANE(ctx, result.PrimaryMXServer)
+	if result.MTASTSMode == "unknown" {
+		result.Valid = false
+		result.Reason = "Domain declares MTA-STS but its policy could not be retrieved"
+		return result
+	}
+	if result.MTASTSMode == "enforce" && !result.MTASTSValid {
+		result.Valid = false
+		result.Reason = "Domain enforces MTA-STS but the live MX does not satisfy its policy"
+		return result
+	}
+	if result.DANEEnabled && !result.DANEValid {
+		result.Valid = false
+		result.Reason = "Domain publishes TLSA records but the live MX certificate does not match"
+		return result
+	}
+
+	// An API verifier already determined deliverability above; don't re-probe via RCPT TO.
+	if !apiVerified {
+		result.Deliverability, result.CatchAll = ec.smtpVerifyMailbox(ctx, result.PrimaryMXServer, result.Domain, local)
+		if result.Deliverability == Undeliverable {
+			result.Valid = false
+			result.Reason = "Mailbox does not exist"
+			return result
+		}
+	}
+
 	result.Valid = true
 	result.Reason = "Email is valid and domain supports secure mail delivery"
 	return result
@@ -129,68 +253,97 @@ func (ec *EmailChecker) extractDomain(email string) string {
 	return strings.ToLower(strings.TrimSpace(parts[1]))
 }
 
-func (ec *EmailChecker) hasMXRecord(domain string) bool {
-	mxRecords, err := net.LookupMX(domain)
-	if err != nil {
-		if ec.config.Verbose {
-			fmt.Printf("MX lookup failed for %s: %v\n", domain, err)
-		}
-		return false
+func (ec *EmailChecker) extractLocalPart(email string) string {
+	parts := strings.Split(email, "@")
+	if len(parts) != 2 {
+		return ""
 	}
-	return len(mxRecords) > 0
+	return parts[0]
 }
 
-func (ec *EmailChecker) hasDNSSEC(domain string) bool {
-	// Use dig command to check DNSSEC
-	cmd := exec.Command("dig", "+dnssec", "+short", "SOA", domain)
-	output, err := cmd.Output()
+func (ec *EmailChecker) hasMXRecord(ctx context.Context, domain string) bool {
+	mxRecords, err := ec.resolver.LookupMX(ctx, domain)
 	if err != nil {
 		if ec.config.Verbose {
-			fmt.Printf("DNSSEC check failed for %s: %v\n", domain, err)
+			fmt.Printf("MX lookup failed for %s: %v\n", domain, err)
 		}
 		return false
 	}
-	
-	outputStr := string(output)
-	// Look for RRSIG record which indicates DNSSEC is enabled
-	return strings.Contains(outputStr, "RRSIG") || 
-		   strings.Contains(strings.ToUpper(outputStr), "RRSIG")
+	return len(mxRecords) > 0
 }
 
-func (ec *EmailChecker) getPrimaryMXServer(domain string) string {
-	mxRecords, err := net.LookupMX(domain)
+// resolveMXServers returns domain's MX hosts ordered from lowest to highest preference
+// number (i.e. most to least preferred), with trailing dots stripped, alongside any
+// misconfiguration issues detected in the MX set (see mxHostIssues).
+func (ec *EmailChecker) resolveMXServers(ctx context.Context, domain string) (hosts []string, misconfigured bool, issues []string) {
+	mxRecords, err := ec.resolver.LookupMX(ctx, domain)
 	if err != nil || len(mxRecords) == 0 {
 		if ec.config.Verbose {
 			fmt.Printf("Failed to get MX records for %s: %v\n", domain, err)
 		}
-		return ""
+		return nil, false, nil
 	}
 
-	// Find MX record with lowest priority number (highest priority)
-	var primaryMX *net.MX
-	for _, mx := range mxRecords {
-		if primaryMX == nil || mx.Pref < primaryMX.Pref {
-			primaryMX = mx
+	sorted := make([]*net.MX, len(mxRecords))
+	copy(sorted, mxRecords)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Pref < sorted[j].Pref })
+
+	for _, mx := range sorted {
+		if mx.Host == "." {
+			issues = append(issues, "Domain advertises a null MX (RFC 7505), explicitly refusing mail")
+			continue
 		}
+
+		host := strings.TrimSuffix(mx.Host, ".")
+		issues = append(issues, ec.mxHostIssues(ctx, domain, host)...)
+		hosts = append(hosts, host)
 	}
 
-	if primaryMX != nil {
-		// Remove trailing dot if present
-		host := primaryMX.Host
-		if strings.HasSuffix(host, ".") {
-			host = host[:len(host)-1]
-		}
-		return host
+	return hosts, len(issues) > 0, issues
+}
+
+// mxHostIssues flags common MX misconfigurations for a single MX host: pointing at
+// localhost, violating RFC 2181 section 10.3 by being a CNAME, or resolving only to
+// private, loopback, link-local or unspecified addresses. An MX pointing at the domain's
+// own A record (e.g. "example.com MX 10 example.com.") is a valid, common small-mail-setup
+// configuration and is not flagged; the RFC 7505 null MX sentinel is handled separately in
+// resolveMXServers since it never reaches this function.
+func (ec *EmailChecker) mxHostIssues(ctx context.Context, domain, host string) []string {
+	var issues []string
+
+	if strings.EqualFold(host, "localhost") {
+		issues = append(issues, fmt.Sprintf("MX host %s is localhost", host))
+	}
+
+	if cname, err := net.DefaultResolver.LookupCNAME(ctx, host); err == nil && !strings.EqualFold(strings.TrimSuffix(cname, "."), host) {
+		issues = append(issues, fmt.Sprintf("MX host %s is a CNAME (RFC 2181 section 10.3 violation)", host))
 	}
 
-	return ""
+	if addrs, err := net.DefaultResolver.LookupHost(ctx, host); err == nil && len(addrs) > 0 && allPrivateOrLocal(addrs) {
+		issues = append(issues, fmt.Sprintf("MX host %s resolves to %s", host, addrs[0]))
+	}
+
+	return issues
+}
+
+// allPrivateOrLocal reports whether every address in ipStrs is RFC1918/loopback/
+// link-local/unspecified, i.e. unreachable from the public internet.
+func allPrivateOrLocal(ipStrs []string) bool {
+	for _, s := range ipStrs {
+		ip := net.ParseIP(s)
+		if ip == nil || !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()) {
+			return false
+		}
+	}
+	return true
 }
 
-func (ec *EmailChecker) smtpSupportsSTARTTLS(mxServer string) bool {
+func (ec *EmailChecker) smtpSupportsSTARTTLS(ctx context.Context, mxServer string) bool {
 	timeout := time.Duration(ec.config.Timeout) * time.Second
-	
+
 	// Try to connect to SMTP server on port 25
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:25", mxServer), timeout)
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:25", mxServer))
 	if err != nil {
 		if ec.config.Verbose {
 			fmt.Printf("Failed to connect to SMTP server %s: %v\n", mxServer, err)
@@ -198,6 +351,7 @@ func (ec *EmailChecker) smtpSupportsSTARTTLS(mxServer string) bool {
 		return false
 	}
 	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
 
 	// Create SMTP client
 	client, err := smtp.NewClient(conn, mxServer)
@@ -213,3 +367,92 @@ func (ec *EmailChecker) smtpSupportsSTARTTLS(mxServer string) bool {
 	ok, _ := client.Extension("STARTTLS")
 	return ok
 }
+
+// smtpVerifyMailbox runs an EHLO/MAIL FROM/RCPT TO conversation against mxServer to
+// determine whether local@domain is deliverable, and issues a second RCPT TO against a
+// random local part to detect catch-all domains.
+func (ec *EmailChecker) smtpVerifyMailbox(ctx context.Context, mxServer, domain, local string) (Deliverability, bool) {
+	timeout := time.Duration(ec.config.Timeout) * time.Second
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:25", mxServer))
+	if err != nil {
+		if ec.config.Verbose {
+			fmt.Printf("Failed to connect to SMTP server %s: %v\n", mxServer, err)
+		}
+		return Unknown, false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, mxServer)
+	if err != nil {
+		if ec.config.Verbose {
+			fmt.Printf("Failed to create SMTP client for %s: %v\n", mxServer, err)
+		}
+		return Unknown, false
+	}
+	defer client.Quit()
+
+	if err := client.Hello(ec.config.HelloName); err != nil {
+		if ec.config.Verbose {
+			fmt.Printf("EHLO failed for %s: %v\n", mxServer, err)
+		}
+		return Unknown, false
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: mxServer}); err != nil && ec.config.Verbose {
+			fmt.Printf("STARTTLS failed during mailbox verification for %s: %v\n", mxServer, err)
+		}
+	}
+
+	if err := client.Mail(ec.config.FromEmail); err != nil {
+		if ec.config.Verbose {
+			fmt.Printf("MAIL FROM rejected by %s: %v\n", mxServer, err)
+		}
+		return Unknown, false
+	}
+
+	status := classifySMTPResponse(client.Rcpt(local + "@" + domain))
+	if status != Deliverable {
+		return status, false
+	}
+
+	catchAllStatus := classifySMTPResponse(client.Rcpt(randomLocalPart() + "@" + domain))
+	return status, catchAllStatus == Deliverable
+}
+
+// randomLocalPart generates a local part that is extremely unlikely to exist, used to
+// probe whether a domain accepts mail for any recipient (a catch-all configuration).
+func randomLocalPart() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("sev-probe-%d", time.Now().UnixNano())
+	}
+	return "sev-probe-" + hex.EncodeToString(b)
+}
+
+// classifySMTPResponse maps an SMTP command error into a Deliverability verdict.
+// A nil error (2xx response) means deliverable, 4xx is treated as unknown since it
+// typically indicates greylisting rather than a genuinely missing mailbox, and 5xx
+// means the server rejected the recipient outright.
+func classifySMTPResponse(err error) Deliverability {
+	if err == nil {
+		return Deliverable
+	}
+
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		switch {
+		case protoErr.Code >= 200 && protoErr.Code < 300:
+			return Deliverable
+		case protoErr.Code >= 400 && protoErr.Code < 500:
+			return Unknown
+		case protoErr.Code >= 500 && protoErr.Code < 600:
+			return Undeliverable
+		}
+	}
+
+	return Unknown
+}
@@ -1,6 +1,7 @@
 package checker
 
 import (
+	"net/textproto"
 	"testing"
 
 	"github.com/kelvinzer0/secure-email-validator/internal/config"
@@ -80,3 +81,76 @@ func TestEmailChecker_extractDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestEmailChecker_extractLocalPart(t *testing.T) {
+	ec := NewEmailChecker(config.DefaultConfig())
+
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"test@example.com", "test"},
+		{"user@DOMAIN.COM", "user"},
+		{"invalid-email", ""},
+		{"test@", "test"},
+		{"@example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := ec.extractLocalPart(tt.email); got != tt.want {
+				t.Errorf("extractLocalPart(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllPrivateOrLocal(t *testing.T) {
+	tests := []struct {
+		name string
+		ips  []string
+		want bool
+	}{
+		{"loopback", []string{"127.0.0.1"}, true},
+		{"rfc1918", []string{"10.0.0.5"}, true},
+		{"link-local", []string{"169.254.1.1"}, true},
+		{"unspecified", []string{"0.0.0.0"}, true},
+		{"public", []string{"8.8.8.8"}, false},
+		{"mixed", []string{"10.0.0.5", "8.8.8.8"}, false},
+		{"invalid", []string{"not-an-ip"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allPrivateOrLocal(tt.ips); got != tt.want {
+				t.Errorf("allPrivateOrLocal(%v) = %v, want %v", tt.ips, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifySMTPResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Deliverability
+	}{
+		{"nil error", nil, Deliverable},
+		{"250 OK", &textproto.Error{Code: 250, Msg: "OK"}, Deliverable},
+		{"450 greylisted", &textproto.Error{Code: 450, Msg: "try again later"}, Unknown},
+		{"550 no mailbox", &textproto.Error{Code: 550, Msg: "no such user"}, Undeliverable},
+		{"unrecognized error", errMockSMTP{}, Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifySMTPResponse(tt.err); got != tt.want {
+				t.Errorf("classifySMTPResponse(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+type errMockSMTP struct{}
+
+func (errMockSMTP) Error() string { return "mock smtp error" }
@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GmailVerifier checks mailbox existence for Google-hosted domains using the public
+// account-existence probe, since Gmail's SMTP servers accept RCPT TO for any recipient.
+type GmailVerifier struct {
+	HTTPClient *http.Client
+}
+
+// NewGmailVerifier creates a GmailVerifier with a sane default HTTP client timeout.
+func NewGmailVerifier() *GmailVerifier {
+	return &GmailVerifier{HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// IsSupported reports whether mxHost belongs to Google's mail infrastructure.
+func (v *GmailVerifier) IsSupported(mxHost string) bool {
+	mxHost = strings.ToLower(mxHost)
+	return strings.HasSuffix(mxHost, ".google.com") || strings.HasSuffix(mxHost, ".googlemail.com")
+}
+
+// Check queries Google's account-existence endpoint for local@domain.
+func (v *GmailVerifier) Check(ctx context.Context, domain, local string) (*ValidationResult, error) {
+	email := local + "@" + domain
+	endpoint := "https://mail.google.com/mail/gxlu?email=" + url.QueryEscape(email)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building gmail probe request: %w", err)
+	}
+
+	resp, err := v.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gmail probe request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	result := &ValidationResult{Deliverability: Undeliverable}
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "COMPASS" {
+			result.Deliverability = Deliverable
+			break
+		}
+	}
+	return result, nil
+}
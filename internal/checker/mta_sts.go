@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mtaSTSPolicy is a parsed RFC 8461 MTA-STS policy document.
+type mtaSTSPolicy struct {
+	version    string
+	mode       string
+	mxPatterns []string
+	maxAge     int
+}
+
+// checkMTASTS fetches and validates domain's MTA-STS policy, confirming that mxHost (the
+// live primary MX) matches one of the policy's mx: patterns. It returns the declared
+// mode and whether mxHost satisfies that policy, regardless of mode; it's the caller's
+// job to decide whether a "testing"-mode mismatch should actually fail validation.
+//
+// mode is "none" when the _mta-sts TXT lookup fails (the domain hasn't published the
+// record), or when a fetched policy itself declares mode none. Once that record exists, the domain has declared MTA-STS, so a policy fetch that
+// fails, 404s, or fails to parse is reported as mode "unknown" with valid=false rather
+// than silently downgraded to "none" — an attacker stripping the policy response
+// shouldn't be able to make a declared domain look like it never opted in.
+func (ec *EmailChecker) checkMTASTS(ctx context.Context, domain, mxHost string) (mode string, valid bool) {
+	if _, err := ec.resolver.LookupTXT(ctx, "_mta-sts."+domain); err != nil {
+		if ec.config.Verbose {
+			fmt.Printf("No _mta-sts TXT record for %s: %v\n", domain, err)
+		}
+		return "none", false
+	}
+
+	client := &http.Client{Timeout: time.Duration(ec.config.Timeout) * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://mta-sts.%s/.well-known/mta-sts.txt", domain), nil)
+	if err != nil {
+		return "unknown", false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ec.config.Verbose {
+			fmt.Printf("Fetching MTA-STS policy for %s failed: %v\n", domain, err)
+		}
+		return "unknown", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "unknown", false
+	}
+
+	policy := parseMTASTSPolicy(resp.Body)
+	if policy.version != "STSv1" {
+		return "unknown", false
+	}
+
+	matches := false
+	for _, pattern := range policy.mxPatterns {
+		if matchesMXPattern(pattern, mxHost) {
+			matches = true
+			break
+		}
+	}
+
+	return policy.mode, matches
+}
+
+func parseMTASTSPolicy(r io.Reader) mtaSTSPolicy {
+	var policy mtaSTSPolicy
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+		switch key {
+		case "version":
+			policy.version = value
+		case "mode":
+			policy.mode = value
+		case "mx":
+			policy.mxPatterns = append(policy.mxPatterns, value)
+		case "max_age":
+			if n, err := strconv.Atoi(value); err == nil {
+				policy.maxAge = n
+			}
+		}
+	}
+
+	return policy
+}
+
+// matchesMXPattern reports whether mxHost matches an MTA-STS mx: pattern, which is
+// either an exact hostname or a "*.example.com" wildcard covering exactly one label
+// per RFC 8461 section 4.1.
+func matchesMXPattern(pattern, mxHost string) bool {
+	pattern = strings.ToLower(pattern)
+	mxHost = strings.ToLower(mxHost)
+
+	if strings.HasPrefix(pattern, "*.") {
+		suffix := pattern[2:]
+		dot := strings.Index(mxHost, ".")
+		if dot < 0 {
+			return false
+		}
+		return mxHost[dot+1:] == suffix
+	}
+	return pattern == mxHost
+}
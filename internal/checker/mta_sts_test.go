@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchesMXPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		mxHost  string
+		want    bool
+	}{
+		{"mail.example.com", "mail.example.com", true},
+		{"mail.example.com", "MAIL.EXAMPLE.COM", true},
+		{"*.example.com", "mail.example.com", true},
+		{"*.example.com", "a.b.example.com", false},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "mail.other.com", false},
+		{"mail.example.com", "mail.other.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.mxHost, func(t *testing.T) {
+			if got := matchesMXPattern(tt.pattern, tt.mxHost); got != tt.want {
+				t.Errorf("matchesMXPattern(%q, %q) = %v, want %v", tt.pattern, tt.mxHost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMTASTSPolicy(t *testing.T) {
+	doc := "version: STSv1\nmode: enforce\nmx: mail.example.com\nmx: *.backup.example.com\nmax_age: 604800\n"
+
+	policy := parseMTASTSPolicy(strings.NewReader(doc))
+
+	if policy.version != "STSv1" {
+		t.Errorf("version = %q, want STSv1", policy.version)
+	}
+	if policy.mode != "enforce" {
+		t.Errorf("mode = %q, want enforce", policy.mode)
+	}
+	if policy.maxAge != 604800 {
+		t.Errorf("maxAge = %d, want 604800", policy.maxAge)
+	}
+	wantMX := []string{"mail.example.com", "*.backup.example.com"}
+	if len(policy.mxPatterns) != len(wantMX) {
+		t.Fatalf("mxPatterns = %v, want %v", policy.mxPatterns, wantMX)
+	}
+	for i, mx := range wantMX {
+		if policy.mxPatterns[i] != mx {
+			t.Errorf("mxPatterns[%d] = %q, want %q", i, policy.mxPatterns[i], mx)
+		}
+	}
+}
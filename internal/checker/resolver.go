@@ -0,0 +1,119 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+type mxCacheEntry struct {
+	records   []*net.MX
+	expiresAt time.Time
+}
+
+type txtCacheEntry struct {
+	records   []string
+	expiresAt time.Time
+}
+
+type dnssecCacheEntry struct {
+	status    DNSSECStatus
+	expiresAt time.Time
+}
+
+// Resolver wraps MX, TXT and DNSSEC lookups with an in-memory, TTL-based cache so that
+// validating many addresses in the same domain (bulk validation, or the HTTP server
+// under load) doesn't re-issue the same DNS queries for every address. It is safe for
+// concurrent use so a single EmailChecker can be shared across goroutines.
+type Resolver struct {
+	ttl time.Duration
+
+	mu          sync.RWMutex
+	mxCache     map[string]mxCacheEntry
+	txtCache    map[string]txtCacheEntry
+	dnssecCache map[string]dnssecCacheEntry
+}
+
+// NewResolver creates a Resolver whose cache entries expire after ttl. A non-positive
+// ttl falls back to a 15 minute default.
+func NewResolver(ttl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	return &Resolver{
+		ttl:         ttl,
+		mxCache:     make(map[string]mxCacheEntry),
+		txtCache:    make(map[string]txtCacheEntry),
+		dnssecCache: make(map[string]dnssecCacheEntry),
+	}
+}
+
+// LookupMX returns domain's MX records, serving a cached result when it hasn't expired.
+// If a fresh lookup fails and a stale entry exists, the stale entry is returned instead
+// of propagating the error.
+func (r *Resolver) LookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
+	r.mu.RLock()
+	entry, ok := r.mxCache[domain]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.records, nil
+	}
+
+	records, err := net.DefaultResolver.LookupMX(ctx, domain)
+	if err != nil {
+		if ok {
+			return entry.records, nil
+		}
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.mxCache[domain] = mxCacheEntry{records: records, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return records, nil
+}
+
+// LookupTXT returns the TXT records for name, serving a cached result when it hasn't
+// expired and falling back to a stale entry if a fresh lookup fails.
+func (r *Resolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.mu.RLock()
+	entry, ok := r.txtCache[name]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.records, nil
+	}
+
+	records, err := net.DefaultResolver.LookupTXT(ctx, name)
+	if err != nil {
+		if ok {
+			return entry.records, nil
+		}
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.txtCache[name] = txtCacheEntry{records: records, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return records, nil
+}
+
+// CachedDNSSEC returns a cached DNSSEC status for domain, if present and unexpired.
+func (r *Resolver) CachedDNSSEC(domain string) (DNSSECStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.dnssecCache[domain]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false
+	}
+	return entry.status, true
+}
+
+// CacheDNSSEC stores status for domain, to be served by CachedDNSSEC until it expires.
+func (r *Resolver) CacheDNSSEC(domain string, status DNSSECStatus) {
+	r.mu.Lock()
+	r.dnssecCache[domain] = dnssecCacheEntry{status: status, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+}
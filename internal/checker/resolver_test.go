@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestResolver_LookupMX_cacheHit(t *testing.T) {
+	r := NewResolver(time.Minute)
+	want := []*net.MX{{Host: "mail.example.com.", Pref: 10}}
+	r.mxCache["example.com"] = mxCacheEntry{records: want, expiresAt: time.Now().Add(time.Minute)}
+
+	got, err := r.LookupMX(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("LookupMX returned error for a fresh cache entry: %v", err)
+	}
+	if len(got) != 1 || got[0].Host != want[0].Host {
+		t.Errorf("LookupMX(%q) = %v, want %v", "example.com", got, want)
+	}
+}
+
+func TestResolver_LookupMX_staleFallbackOnError(t *testing.T) {
+	r := NewResolver(time.Minute)
+	stale := []*net.MX{{Host: "mail.invalid-tld-for-testing.invalid.", Pref: 10}}
+	domain := "invalid-tld-for-testing.invalid"
+	r.mxCache[domain] = mxCacheEntry{records: stale, expiresAt: time.Now().Add(-time.Minute)}
+
+	got, err := r.LookupMX(context.Background(), domain)
+	if err != nil {
+		t.Fatalf("LookupMX should fall back to the stale entry instead of erroring, got: %v", err)
+	}
+	if len(got) != 1 || got[0].Host != stale[0].Host {
+		t.Errorf("LookupMX(%q) = %v, want stale entry %v", domain, got, stale)
+	}
+}
+
+func TestResolver_DNSSECCache(t *testing.T) {
+	r := NewResolver(time.Minute)
+
+	if _, ok := r.CachedDNSSEC("example.com"); ok {
+		t.Fatal("expected no cached DNSSEC status before CacheDNSSEC is called")
+	}
+
+	r.CacheDNSSEC("example.com", DNSSECSecure)
+
+	status, ok := r.CachedDNSSEC("example.com")
+	if !ok || status != DNSSECSecure {
+		t.Errorf("CachedDNSSEC(%q) = (%q, %v), want (%q, true)", "example.com", status, ok, DNSSECSecure)
+	}
+}
@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// YahooVerifier checks mailbox existence for Yahoo-hosted domains using Yahoo's
+// account-lookup endpoint, since Yahoo's SMTP servers accept RCPT TO for any recipient.
+type YahooVerifier struct {
+	HTTPClient *http.Client
+}
+
+// NewYahooVerifier creates a YahooVerifier with a sane default HTTP client timeout.
+func NewYahooVerifier() *YahooVerifier {
+	return &YahooVerifier{HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// IsSupported reports whether mxHost belongs to Yahoo's mail infrastructure.
+func (v *YahooVerifier) IsSupported(mxHost string) bool {
+	return strings.HasSuffix(strings.ToLower(mxHost), ".yahoodns.net")
+}
+
+// Check queries Yahoo's account-lookup endpoint for local@domain.
+func (v *YahooVerifier) Check(ctx context.Context, domain, local string) (*ValidationResult, error) {
+	email := local + "@" + domain
+	endpoint := "https://login.yahoo.com/account/module/webauthn?validateField=username&username=" + url.QueryEscape(email)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building yahoo probe request: %w", err)
+	}
+
+	resp, err := v.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("yahoo probe request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Errors []struct {
+			Name string `json:"name"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decoding yahoo probe response: %w", err)
+	}
+
+	result := &ValidationResult{Deliverability: Undeliverable}
+	for _, e := range payload.Errors {
+		if e.Name == "IDENTIFIER_EXISTS" {
+			result.Deliverability = Deliverable
+			break
+		}
+	}
+	return result, nil
+}
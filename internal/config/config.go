@@ -1,15 +1,29 @@
 package config
 
+import "time"
+
 // Config holds the configuration for the email checker
 type Config struct {
-	Timeout int  // SMTP connection timeout in seconds
-	Verbose bool // Enable verbose logging
+	Timeout            int           // SMTP connection timeout in seconds
+	Verbose            bool          // Enable verbose logging
+	FromEmail          string        // Envelope sender used for SMTP MAIL FROM during mailbox verification
+	HelloName          string        // Hostname used for SMTP EHLO/HELO during mailbox verification
+	EnableAPIVerifiers bool          // Opt-in to provider API probes (Gmail, Yahoo, ...) which can be rate-limited
+	DNSSECResolvers    []string      // Recursive resolvers queried for DNSSEC validation
+	DNSSECStrict       bool          // When true, walk the DS/DNSKEY chain ourselves instead of trusting the resolver's AD bit
+	CacheTTL           time.Duration // How long MX/TXT/DNSSEC lookups are cached before being re-queried
 }
 
 // DefaultConfig returns a configuration with default values
 func DefaultConfig() *Config {
 	return &Config{
-		Timeout: 10,
-		Verbose: false,
+		Timeout:            10,
+		Verbose:            false,
+		FromEmail:          "verify@example.com",
+		HelloName:          "localhost",
+		EnableAPIVerifiers: false,
+		DNSSECResolvers:    []string{"1.1.1.1:53", "8.8.8.8:53"},
+		DNSSECStrict:       false,
+		CacheTTL:           15 * time.Minute,
 	}
 }
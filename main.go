@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/kelvinzer0/secure-email-validator/internal/checker"
 	"github.com/kelvinzer0/secure-email-validator/internal/config"
@@ -14,13 +19,21 @@ import (
 
 func main() {
 	var (
-		email      = flag.String("email", "", "Email address to validate")
-		verbose    = flag.Bool("verbose", false, "Enable verbose output")
-		timeout    = flag.Int("timeout", 10, "SMTP connection timeout in seconds")
-		jsonOutput = flag.Bool("json", false, "Output result in JSON format")
-		server     = flag.Bool("server", false, "Run as HTTP server")
-		port       = flag.String("port", "8587", "Server port (only used with -server)")
-		help       = flag.Bool("help", false, "Show help message")
+		email           = flag.String("email", "", "Email address to validate")
+		verbose         = flag.Bool("verbose", false, "Enable verbose output")
+		timeout         = flag.Int("timeout", 10, "SMTP connection timeout in seconds")
+		jsonOutput      = flag.Bool("json", false, "Output result in JSON format")
+		server          = flag.Bool("server", false, "Run as HTTP server")
+		port            = flag.String("port", "8587", "Server port (only used with -server)")
+		fromEmail       = flag.String("from", "verify@example.com", "Envelope sender used for SMTP MAIL FROM during mailbox verification")
+		helloName       = flag.String("hello", "localhost", "Hostname used for SMTP EHLO/HELO during mailbox verification")
+		apiVerify       = flag.Bool("api-verify", false, "Enable provider API verifiers (Gmail, Yahoo) instead of relying on SMTP RCPT TO; these can be rate-limited")
+		dnssecResolvers = flag.String("dnssec-resolvers", "1.1.1.1:53,8.8.8.8:53", "Comma-separated list of DNSSEC-validating resolvers to query")
+		dnssecStrict    = flag.Bool("dnssec-strict", false, "Walk the DS/DNSKEY chain ourselves instead of trusting the resolver's AD bit")
+		cacheTTL        = flag.Duration("cache-ttl", 15*time.Minute, "How long MX/TXT/DNSSEC lookups are cached before being re-queried")
+		file            = flag.String("file", "", "Path to a file with one email address per line; validates them concurrently and writes NDJSON to stdout")
+		concurrency     = flag.Int("concurrency", 10, "Number of addresses to validate concurrently (only used with -file)")
+		help            = flag.Bool("help", false, "Show help message")
 	)
 	flag.Parse()
 
@@ -35,6 +48,26 @@ func main() {
 		return
 	}
 
+	cfg := &config.Config{
+		Timeout:            *timeout,
+		Verbose:            *verbose,
+		FromEmail:          *fromEmail,
+		HelloName:          *helloName,
+		EnableAPIVerifiers: *apiVerify,
+		DNSSECResolvers:    strings.Split(*dnssecResolvers, ","),
+		DNSSECStrict:       *dnssecStrict,
+		CacheTTL:           *cacheTTL,
+	}
+
+	// Bulk CLI mode
+	if *file != "" {
+		if err := validateFile(cfg, *file, *concurrency); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// CLI mode
 	if *email == "" {
 		fmt.Println("Error: Email address is required")
@@ -42,13 +75,8 @@ func main() {
 		os.Exit(1)
 	}
 
-	cfg := &config.Config{
-		Timeout: *timeout,
-		Verbose: *verbose,
-	}
-
 	emailChecker := checker.NewEmailChecker(cfg)
-	result := emailChecker.ValidateEmail(*email)
+	result := emailChecker.ValidateEmail(context.Background(), *email)
 
 	if *jsonOutput {
 		jsonResult, err := json.MarshalIndent(result, "", "  ")
@@ -85,27 +113,46 @@ func printHumanReadableResult(result *checker.ValidationResult, originalEmail st
 		fmt.Println("\n--- Detailed Information ---")
 		fmt.Printf("Domain: %s\n", result.Domain)
 		fmt.Printf("Has MX Record: %t\n", result.HasMXRecord)
-		fmt.Printf("Has DNSSEC: %t\n", result.HasDNSSEC)
+		fmt.Printf("DNSSEC Status: %s\n", result.DNSSECStatus)
 		fmt.Printf("Primary MX Server: %s\n", result.PrimaryMXServer)
+		fmt.Printf("Attempted MX Servers: %s\n", strings.Join(result.AttemptedMXServers, ", "))
+		fmt.Printf("Misconfigured MX: %t\n", result.MisconfiguredMX)
+		for _, issue := range result.MXIssues {
+			fmt.Printf("  - %s\n", issue)
+		}
 		fmt.Printf("Supports STARTTLS: %t\n", result.SupportsSTARTTLS)
+		fmt.Printf("MTA-STS Mode: %s (policy satisfied: %t)\n", result.MTASTSMode, result.MTASTSValid)
+		fmt.Printf("DANE/TLSA: enabled=%t valid=%t\n", result.DANEEnabled, result.DANEValid)
+		fmt.Printf("Deliverability: %s\n", result.Deliverability)
+		fmt.Printf("Catch-All Domain: %t\n", result.CatchAll)
 	}
 }
 
 func startServer(port string) {
-	http.HandleFunc("/validate", handleValidation)
+	// Shared across every request so repeated lookups in the same domain are served
+	// from cache instead of re-querying MX/TXT/DNSSEC for every address.
+	resolver := checker.NewResolver(config.DefaultConfig().CacheTTL)
+
+	http.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) {
+		handleValidation(w, r, resolver)
+	})
+	http.HandleFunc("/validate/batch", func(w http.ResponseWriter, r *http.Request) {
+		handleValidationBatch(w, r, resolver)
+	})
 	http.HandleFunc("/health", handleHealth)
-	
+
 	fmt.Printf("🚀 Secure Email Validator Server starting on port %s\n", port)
 	fmt.Printf("📍 Validation endpoint: http://localhost:%s/validate?email=test@example.com\n", port)
+	fmt.Printf("📦 Batch endpoint: POST http://localhost:%s/validate/batch\n", port)
 	fmt.Printf("💚 Health check: http://localhost:%s/health\n", port)
-	
+
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		fmt.Printf("Server failed to start: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func handleValidation(w http.ResponseWriter, r *http.Request) {
+func handleValidation(w http.ResponseWriter, r *http.Request, resolver *checker.Resolver) {
 	email := r.URL.Query().Get("email")
 	if email == "" {
 		http.Error(w, `{"error": "Email parameter required"}`, http.StatusBadRequest)
@@ -122,22 +169,151 @@ func handleValidation(w http.ResponseWriter, r *http.Request) {
 
 	verbose := r.URL.Query().Get("verbose") == "true"
 
-	cfg := &config.Config{
-		Timeout: timeout,
-		Verbose: verbose,
+	cfg := config.DefaultConfig()
+	cfg.Timeout = timeout
+	cfg.Verbose = verbose
+	if from := r.URL.Query().Get("from"); from != "" {
+		cfg.FromEmail = from
 	}
+	if hello := r.URL.Query().Get("hello"); hello != "" {
+		cfg.HelloName = hello
+	}
+	cfg.EnableAPIVerifiers = r.URL.Query().Get("api_verify") == "true"
+	cfg.DNSSECStrict = r.URL.Query().Get("dnssec_strict") == "true"
 
-	emailChecker := checker.NewEmailChecker(cfg)
-	result := emailChecker.ValidateEmail(email)
+	emailChecker := checker.NewEmailCheckerWithResolver(cfg, resolver)
+	result := emailChecker.ValidateEmail(r.Context(), email)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	
+
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		http.Error(w, `{"error": "Failed to encode response"}`, http.StatusInternalServerError)
 	}
 }
 
+// maxBatchConcurrency bounds how many workers a single /validate/batch request may spin
+// up, and maxBatchEmails bounds how many addresses it may submit, so that a request
+// sharing one EmailChecker across goroutines (chunk0-4) can't exhaust outbound SMTP
+// connections or goroutines on its own.
+const (
+	maxBatchConcurrency = 50
+	maxBatchEmails      = 10000
+)
+
+// handleValidationBatch validates a list of addresses against a bounded worker pool
+// sharing a single EmailChecker and Resolver cache, streaming each ValidationResult as
+// newline-delimited JSON as soon as it completes rather than buffering the whole batch.
+// If the client disconnects, r.Context() is canceled and outstanding probes stop.
+func handleValidationBatch(w http.ResponseWriter, r *http.Request, resolver *checker.Resolver) {
+	if r.Method != http.MethodPost {
+		http.Error(w, `{"error": "POST required"}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Emails      []string `json:"emails"`
+		Concurrency int      `json:"concurrency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid JSON body"}`, http.StatusBadRequest)
+		return
+	}
+	if len(req.Emails) == 0 {
+		http.Error(w, `{"error": "emails must be a non-empty array"}`, http.StatusBadRequest)
+		return
+	}
+	if len(req.Emails) > maxBatchEmails {
+		http.Error(w, fmt.Sprintf(`{"error": "emails must contain at most %d addresses"}`, maxBatchEmails), http.StatusBadRequest)
+		return
+	}
+	emailChecker := checker.NewEmailCheckerWithResolver(config.DefaultConfig(), resolver)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	flusher, _ := w.(http.Flusher)
+
+	encoder := json.NewEncoder(w)
+	runBatch(r.Context(), emailChecker, req.Emails, req.Concurrency, func(result *checker.ValidationResult) {
+		encoder.Encode(result)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	})
+}
+
+// runBatch validates emails across a bounded pool of at most concurrency workers, all
+// sharing emailChecker (and therefore its Resolver cache), calling onResult as each
+// completes. onResult is called sequentially from a single goroutine, so it doesn't
+// need its own synchronization. A non-positive concurrency falls back to 1, and a
+// concurrency above maxBatchConcurrency is clamped so a single batch can't spin up an
+// unbounded number of SMTP-dialing goroutines.
+func runBatch(ctx context.Context, emailChecker *checker.EmailChecker, emails []string, concurrency int, onResult func(*checker.ValidationResult)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > maxBatchConcurrency {
+		concurrency = maxBatchConcurrency
+	}
+
+	jobs := make(chan string)
+	results := make(chan *checker.ValidationResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for email := range jobs {
+				results <- emailChecker.ValidateEmail(ctx, email)
+			}
+		}()
+	}
+
+	go func() {
+		for _, email := range emails {
+			jobs <- email
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		onResult(result)
+	}
+}
+
+// validateFile reads one email address per line from path and validates them
+// concurrently over a shared EmailChecker and Resolver, writing each ValidationResult
+// as NDJSON to stdout as soon as it completes.
+func validateFile(cfg *config.Config, path string, concurrency int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var emails []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			emails = append(emails, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	emailChecker := checker.NewEmailChecker(cfg)
+	encoder := json.NewEncoder(os.Stdout)
+
+	runBatch(context.Background(), emailChecker, emails, concurrency, func(result *checker.ValidationResult) {
+		encoder.Encode(result)
+	})
+	return nil
+}
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -159,6 +335,14 @@ func showHelp() {
 	fmt.Println("  -verbose         Enable verbose output")
 	fmt.Println("  -timeout int     SMTP connection timeout in seconds (default 10)")
 	fmt.Println("  -json           Output result in JSON format")
+	fmt.Println("  -from string     Envelope sender for SMTP MAIL FROM (default verify@example.com)")
+	fmt.Println("  -hello string    Hostname for SMTP EHLO/HELO (default localhost)")
+	fmt.Println("  -api-verify     Use provider API verifiers (Gmail, Yahoo) instead of SMTP RCPT TO")
+	fmt.Println("  -dnssec-resolvers string  Comma-separated DNSSEC-validating resolvers (default 1.1.1.1:53,8.8.8.8:53)")
+	fmt.Println("  -dnssec-strict  Walk the DS/DNSKEY chain ourselves instead of trusting the AD bit")
+	fmt.Println("  -cache-ttl duration  How long MX/TXT/DNSSEC lookups are cached (default 15m)")
+	fmt.Println("  -file string     Path to a file with one email per line; validates concurrently, writes NDJSON to stdout")
+	fmt.Println("  -concurrency int Number of addresses to validate concurrently with -file (default 10)")
 	fmt.Println("  -help           Show this help message")
 	fmt.Println("\nServer Options:")
 	fmt.Println("  -server         Run as HTTP server")
@@ -167,8 +351,10 @@ func showHelp() {
 	fmt.Println("  # CLI mode")
 	fmt.Println("  email-checker -email john.doe@gmail.com -verbose")
 	fmt.Println("  email-checker -email test@example.com -json")
+	fmt.Println("  email-checker -file addresses.txt -concurrency 20 > results.ndjson")
 	fmt.Println("")
 	fmt.Println("  # Server mode")
 	fmt.Println("  email-checker -server -port 3000")
 	fmt.Println("  curl 'http://localhost:8587/validate?email=test@gmail.com&verbose=true'")
+	fmt.Println(`  curl -X POST http://localhost:8587/validate/batch -d '{"emails":["a@example.com","b@example.com"],"concurrency":5}'`)
 }